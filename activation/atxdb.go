@@ -238,11 +238,12 @@ func (db *DB) createTraversalActiveSetCounterFunc(countedAtxs map[string]types.A
 // CalcActiveSetSize - returns the active set size that matches the view of the contextually valid blocks in the provided layer
 func (db *DB) CalcActiveSetSize(epoch types.EpochID, blocks map[types.BlockID]struct{}) (map[string]struct{}, error) {
 
-	if epoch == 0 {
+	prevEpoch, ok := epoch.Prev()
+	if !ok {
 		return nil, errors.New("tried to retrieve active set for epoch 0")
 	}
 
-	firstLayerOfPrevEpoch := (epoch - 1).FirstLayer()
+	firstLayerOfPrevEpoch := prevEpoch.FirstLayer()
 
 	countedAtxs := make(map[string]types.ATXID)
 	penalties := make(map[string]struct{})
@@ -1608,6 +1608,18 @@ func TestTransactionService(t *testing.T) {
 			require.Equal(t, codes.InvalidArgument, statusCode)
 			require.Contains(t, err.Error(), "`Transaction` origin account not found")
 		}},
+		{"SubmitTransaction_FeeExceedsGasLimit", func(t *testing.T) {
+			tx, err := types.NewSignedTx(0, addr1, 1, defaultGasLimit, defaultGasLimit+1, signing.NewEdSigner())
+			require.NoError(t, err)
+			serializedTx, err := types.InterfaceToBytes(tx)
+			require.NoError(t, err, "error serializing tx")
+			_, err = c.SubmitTransaction(context.Background(), &pb.SubmitTransactionRequest{
+				Transaction: serializedTx,
+			})
+			statusCode := status.Code(err)
+			require.Equal(t, codes.InvalidArgument, statusCode)
+			require.Contains(t, err.Error(), types.ErrFeeExceedsGasLimit.Error())
+		}},
 		{"TransactionsState_MissingTransactionId", func(t *testing.T) {
 			_, err = c.TransactionsState(context.Background(), &pb.TransactionsStateRequest{})
 			statusCode := status.Code(err)
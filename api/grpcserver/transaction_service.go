@@ -71,6 +71,10 @@ func (s TransactionService) SubmitTransaction(_ context.Context, in *pb.SubmitTr
 		return nil, status.Error(codes.InvalidArgument,
 			"`Transaction` must contain a valid, serialized transaction")
 	}
+	if err := tx.Validate(); err != nil {
+		log.Error("invalid tx: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "`Transaction` is invalid: %v", err)
+	}
 	if !s.Mesh.AddressExists(tx.Origin()) {
 		log.With().Error("tx origin address not found in global state",
 			tx.ID(), log.String("origin", tx.Origin().Short()))
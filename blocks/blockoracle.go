@@ -12,6 +12,10 @@ import (
 	"sync"
 )
 
+// ErrNoATX is returned by BlockEligible when the node has no ATX targeting the layer's epoch, and so can't be eligible
+// for blocks in it.
+var ErrNoATX = errors.New("no ATX targets the epoch")
+
 type activationDB interface {
 	GetNodeAtxIDForEpoch(nodeID types.NodeID, targetEpoch types.EpochID) (types.ATXID, error)
 	GetAtxHeader(id types.ATXID) (*types.ActivationTxHeader, error)
@@ -95,13 +99,19 @@ func (bo *Oracle) BlockEligible(layerID types.LayerID) (types.ATXID, []types.Blo
 func (bo *Oracle) calcEligibilityProofs(epochNumber types.EpochID) error {
 	epochBeacon := bo.beaconProvider.GetBeacon(epochNumber)
 
+	prevEpoch, ok := epochNumber.Prev()
+	if !ok {
+		return fmt.Errorf("cannot calculate eligibility for epoch %v, it has no previous epoch", epochNumber)
+	}
+
 	// get the previous epochs total ATXs
-	activeSet := bo.atxDB.GetEpochAtxs(epochNumber - 1)
+	activeSet := bo.atxDB.GetEpochAtxs(prevEpoch)
 	activeSetSize := uint32(len(activeSet))
 	atx, err := bo.getValidAtxForEpoch(epochNumber)
 	if err != nil {
 		if !epochNumber.IsGenesis() {
-			return fmt.Errorf("failed to get latest ATX: %v", err)
+			bo.log.With().Warning("failed to get latest ATX", epochNumber, log.Err(err))
+			return ErrNoATX
 		}
 	} else {
 		bo.atxID = atx.ID()
@@ -173,7 +183,11 @@ func (bo *Oracle) calcEligibilityProofs(epochNumber types.EpochID) error {
 }
 
 func (bo *Oracle) getValidAtxForEpoch(validForEpoch types.EpochID) (*types.ActivationTxHeader, error) {
-	atxID, err := bo.getATXIDForEpoch(validForEpoch - 1)
+	publishEpoch, ok := validForEpoch.Prev()
+	if !ok {
+		return nil, fmt.Errorf("no ATX can target epoch %v", validForEpoch)
+	}
+	atxID, err := bo.getATXIDForEpoch(publishEpoch)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ATX ID for target epoch %v: %v", validForEpoch, err)
 	}
@@ -315,7 +315,8 @@ func (app *SpacemeshApp) setupLogging() {
 		msg += fmt.Sprintf(" with pubsub URL: %s", app.Config.PublishEventsURL)
 	}
 	log.Info(msg)
-	if err := events.InitializeEventReporter(app.Config.PublishEventsURL); err != nil {
+	heartbeat := time.Duration(app.Config.EventsHeartbeat) * time.Second
+	if err := events.InitializeEventReporterWithHeartbeat(app.Config.PublishEventsURL, heartbeat); err != nil {
 		log.Error("unable to initialize event reporter: %s", err)
 	}
 }
@@ -583,11 +584,13 @@ func (app *SpacemeshApp) initServices(nodeID types.NodeID,
 
 	stateAndMeshProjector := pendingtxs.NewStateAndMeshProjector(processor, msh)
 	cfg := miner.Config{
-		Hdist:          app.Config.Hdist,
-		MinerID:        nodeID,
-		AtxsPerBlock:   app.Config.AtxsPerBlock,
-		LayersPerEpoch: layersPerEpoch,
-		TxsPerBlock:    app.Config.TxsPerBlock,
+		Hdist:           app.Config.Hdist,
+		MinerID:         nodeID,
+		AtxsPerBlock:    app.Config.AtxsPerBlock,
+		LayersPerEpoch:  layersPerEpoch,
+		TxsPerBlock:     app.Config.TxsPerBlock,
+		SkipEmptyBlocks: app.Config.SkipEmptyBlocks,
+		MaxBlockBytes:   app.Config.MaxBlockBytes,
 	}
 
 	database.SwitchCreationContext(dbStorepath, "") // currently only blockbuilder uses this mechanism
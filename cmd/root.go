@@ -59,6 +59,8 @@ func AddCommands(cmd *cobra.Command) {
 		config.BlockCacheSize, "size in layers of meshdb block cache")
 	cmd.PersistentFlags().StringVar(&config.PublishEventsURL, "events-url",
 		config.PublishEventsURL, "publish events to this url; if no url specified no events will be published")
+	cmd.PersistentFlags().IntVar(&config.EventsHeartbeat, "events-heartbeat",
+		config.EventsHeartbeat, "report the node status every this many seconds, 0 to disable")
 	cmd.PersistentFlags().BoolVar(&config.Profiler, "profiler",
 		config.Profiler, "enable profiler")
 
@@ -68,6 +70,10 @@ func AddCommands(cmd *cobra.Command) {
 		config.AtxsPerBlock, "the number of atxs to select per block on block creation")
 	cmd.PersistentFlags().IntVar(&config.TxsPerBlock, "txs-per-block",
 		config.TxsPerBlock, "the number of transactions to select per block on block creation")
+	cmd.PersistentFlags().BoolVar(&config.SkipEmptyBlocks, "skip-empty-blocks",
+		config.SkipEmptyBlocks, "don't create blocks when there are no transactions to include")
+	cmd.PersistentFlags().IntVar(&config.MaxBlockBytes, "max-block-bytes",
+		config.MaxBlockBytes, "the maximum size in bytes of a created block, 0 for no limit")
 
 	/** ======================== P2P Flags ========================== **/
 
@@ -24,6 +24,14 @@ func (l EpochID) IsGenesis() bool {
 	return l < 2
 }
 
+// Prev returns the epoch preceding this one. It returns false if this is epoch 0, which has no previous epoch.
+func (l EpochID) Prev() (EpochID, bool) {
+	if l == 0 {
+		return 0, false
+	}
+	return l - 1, true
+}
+
 // FirstLayer returns the layer ID of the first layer in the epoch.
 func (l EpochID) FirstLayer() LayerID {
 	return LayerID(uint64(l) * uint64(getLayersPerEpoch()))
@@ -0,0 +1,15 @@
+package types
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEpochID_Prev(t *testing.T) {
+	_, ok := EpochID(0).Prev()
+	assert.False(t, ok)
+
+	prev, ok := EpochID(5).Prev()
+	assert.True(t, ok)
+	assert.Equal(t, EpochID(4), prev)
+}
@@ -61,6 +61,11 @@ func (l LayerID) GetEpoch() EpochID {
 	return EpochID(uint64(l) / uint64(getLayersPerEpoch()))
 }
 
+// LastInEpoch returns true if this is the last layer in its epoch.
+func (l LayerID) LastInEpoch() bool {
+	return (uint64(l)+1)%uint64(getLayersPerEpoch()) == 0
+}
+
 // GetEffectiveGenesis returns when actual blocks would be created
 func GetEffectiveGenesis() LayerID {
 	return LayerID(atomic.LoadInt32(&EffectiveGenesis))
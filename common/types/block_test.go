@@ -37,3 +37,12 @@ func TestFields(t *testing.T) {
 	b.ActiveSet = &[]ATXID{atx1, atx2, atx3}
 	log.With().Info("got new block", b.Fields()...)
 }
+
+func TestLayerID_LastInEpoch(t *testing.T) {
+	SetLayersPerEpoch(3)
+	for layer, last := range map[LayerID]bool{0: false, 1: false, 2: true, 3: false, 4: false, 5: true} {
+		if layer.LastInEpoch() != last {
+			t.Errorf("layer %d: expected LastInEpoch() == %v", layer, last)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/log"
@@ -187,6 +188,20 @@ func (h Hash32) ShortString() string {
 	return Shorten(h.Hex()[util.Min(2, l):], 10)
 }
 
+// Xor returns the bytewise XOR of the hash and other.
+func (h Hash32) Xor(other Hash32) Hash32 {
+	var res Hash32
+	for i := range h {
+		res[i] = h[i] ^ other[i]
+	}
+	return res
+}
+
+// HasPrefix returns true if the hash begins with prefix.
+func (h Hash32) HasPrefix(prefix []byte) bool {
+	return bytes.HasPrefix(h[:], prefix)
+}
+
 // Shorten shortens a string to a specified length
 func Shorten(s string, maxlen int) string {
 	l := len(s)
@@ -23,3 +23,26 @@ func TestConvert32_20Hash(t *testing.T) {
 	hash20b := cHash32.ToHash20()
 	assert.Equal(t, hash20b, hash20)
 }
+
+func TestHash32_Xor(t *testing.T) {
+	a := CalcHash32([]byte("a"))
+	b := CalcHash32([]byte("b"))
+
+	assert.Equal(t, a, a.Xor(Hash32{}))
+	assert.Equal(t, Hash32{}, a.Xor(a))
+	assert.Equal(t, a.Xor(b), b.Xor(a))
+	assert.Equal(t, b, a.Xor(b).Xor(a))
+}
+
+func TestHash32_HasPrefix(t *testing.T) {
+	h := BytesToHash(append([]byte{0xab, 0xcd, 0xef}, make([]byte, 29)...))
+
+	assert.True(t, h.HasPrefix(nil))
+	assert.True(t, h.HasPrefix([]byte{0xab}))
+	assert.True(t, h.HasPrefix([]byte{0xab, 0xcd}))
+	assert.True(t, h.HasPrefix([]byte{0xab, 0xcd, 0xef}))
+	assert.False(t, h.HasPrefix([]byte{0xab, 0xce}))
+	assert.False(t, h.HasPrefix([]byte{0xcd}))
+	assert.True(t, h.HasPrefix(h.Bytes()))
+	assert.False(t, h.HasPrefix(append(h.Bytes(), 0)))
+}
@@ -1,8 +1,11 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/spacemeshos/ed25519"
+	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"strings"
@@ -117,6 +120,96 @@ func (t *Transaction) String() string {
 		t.ID().ShortString(), t.Origin().Short(), t.Recipient.Short(), t.Amount, t.AccountNonce, t.GasLimit, t.Fee)
 }
 
+// Errors returned by Transaction.Validate
+var (
+	ErrZeroGasLimit       = errors.New("transaction gas limit is zero")
+	ErrFeeExceedsGasLimit = errors.New("transaction fee exceeds gas limit")
+	ErrFeeOverflow        = errors.New("transaction amount plus fee overflows")
+	ErrEmptyRecipient     = errors.New("transaction recipient is empty")
+)
+
+// Validate checks the structural invariants of the transaction that don't depend on state: the gas limit is not zero,
+// the fee (the gas paid) fits within the gas limit, the amount plus fee the origin has to cover doesn't overflow and
+// the recipient is set. It doesn't verify the signature, see CalcAndSetOrigin.
+func (t *Transaction) Validate() error {
+	if t.GasLimit == 0 {
+		return ErrZeroGasLimit
+	}
+	if t.Fee > t.GasLimit {
+		return ErrFeeExceedsGasLimit
+	}
+	if t.Amount+t.Fee < t.Amount {
+		return ErrFeeOverflow
+	}
+	if t.Recipient == (Address{}) {
+		return ErrEmptyRecipient
+	}
+	return nil
+}
+
+// txJSON is the canonical JSON representation of a Transaction.
+type txJSON struct {
+	Nonce     uint64     `json:"nonce"`
+	Recipient util.Bytes `json:"recipient"`
+	Amount    uint64     `json:"amount"`
+	GasLimit  uint64     `json:"gas"`
+	Fee       uint64     `json:"fee"`
+	Signature util.Bytes `json:"signature"`
+	Origin    util.Bytes `json:"origin,omitempty"`
+}
+
+// MarshalJSON encodes the transaction as JSON, with the recipient, signature and origin as 0x-prefixed hex strings.
+// The origin is only included if it's set.
+func (t *Transaction) MarshalJSON() ([]byte, error) {
+	enc := txJSON{
+		Nonce:     t.AccountNonce,
+		Recipient: t.Recipient.Bytes(),
+		Amount:    t.Amount,
+		GasLimit:  t.GasLimit,
+		Fee:       t.Fee,
+		Signature: t.Signature[:],
+	}
+	if t.origin != nil {
+		enc.Origin = t.origin.Bytes()
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a transaction encoded by MarshalJSON. The origin is recalculated from the signature and, if
+// the encoding includes an origin, it must match the recalculated one.
+func (t *Transaction) UnmarshalJSON(input []byte) error {
+	var dec txJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if len(dec.Recipient) != AddressLength {
+		return fmt.Errorf("invalid recipient length: %d", len(dec.Recipient))
+	}
+	if len(dec.Signature) != len(t.Signature) {
+		return fmt.Errorf("invalid signature length: %d", len(dec.Signature))
+	}
+
+	tx := Transaction{
+		InnerTransaction: InnerTransaction{
+			AccountNonce: dec.Nonce,
+			Recipient:    BytesToAddress(dec.Recipient),
+			GasLimit:     dec.GasLimit,
+			Fee:          dec.Fee,
+			Amount:       dec.Amount,
+		},
+	}
+	copy(tx.Signature[:], dec.Signature)
+	if err := tx.CalcAndSetOrigin(); err != nil {
+		return err
+	}
+	if dec.Origin != nil && BytesToAddress(dec.Origin) != tx.Origin() {
+		return fmt.Errorf("origin %s doesn't match signature origin %s", BytesToAddress(dec.Origin).Short(),
+			tx.Origin().Short())
+	}
+	*t = tx
+	return nil
+}
+
 // InnerTransaction includes all of a transaction's fields, except the signature (origin and id aren't stored).
 type InnerTransaction struct {
 	AccountNonce uint64
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/spacemeshos/go-spacemesh/common/util"
+	"github.com/spacemeshos/go-spacemesh/signing"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestTransaction_Validate(t *testing.T) {
+	r := require.New(t)
+	signer := signing.NewEdSigner()
+	recipient := HexToAddress("aaaa")
+
+	tx, err := NewSignedTx(1, recipient, 10, 100, 1, signer)
+	r.NoError(err)
+	r.NoError(tx.Validate())
+
+	tx, err = NewSignedTx(1, recipient, 10, 0, 1, signer)
+	r.NoError(err)
+	r.Equal(ErrZeroGasLimit, tx.Validate())
+
+	tx, err = NewSignedTx(1, recipient, 10, 100, 101, signer)
+	r.NoError(err)
+	r.Equal(ErrFeeExceedsGasLimit, tx.Validate())
+
+	tx, err = NewSignedTx(1, recipient, 10, 100, 100, signer)
+	r.NoError(err)
+	r.NoError(tx.Validate())
+
+	tx, err = NewSignedTx(1, recipient, math.MaxUint64, 100, 1, signer)
+	r.NoError(err)
+	r.Equal(ErrFeeOverflow, tx.Validate())
+
+	tx, err = NewSignedTx(1, Address{}, 10, 100, 1, signer)
+	r.NoError(err)
+	r.Equal(ErrEmptyRecipient, tx.Validate())
+}
+
+func TestTransaction_JSONRoundTrip(t *testing.T) {
+	r := require.New(t)
+	signer := signing.NewEdSigner()
+	tx, err := NewSignedTx(5, HexToAddress("aaaa"), 10, 100, 1, signer)
+	r.NoError(err)
+
+	data, err := json.Marshal(tx)
+	r.NoError(err)
+
+	var fields map[string]interface{}
+	r.NoError(json.Unmarshal(data, &fields))
+	r.Equal(util.Bytes(tx.Recipient.Bytes()).String(), fields["recipient"])
+	r.Equal(util.Bytes(tx.Signature[:]).String(), fields["signature"])
+	r.Equal(util.Bytes(tx.Origin().Bytes()).String(), fields["origin"])
+
+	var decoded Transaction
+	r.NoError(json.Unmarshal(data, &decoded))
+	r.Equal(tx.InnerTransaction, decoded.InnerTransaction)
+	r.Equal(tx.Signature, decoded.Signature)
+	r.Equal(tx.Origin(), decoded.Origin())
+	r.Equal(tx.ID(), decoded.ID())
+
+	fields["origin"] = util.Bytes(HexToAddress("bbbb").Bytes()).String()
+	data, err = json.Marshal(fields)
+	r.NoError(err)
+	r.Error(json.Unmarshal(data, &decoded))
+}
@@ -92,12 +92,18 @@ type BaseConfig struct {
 
 	PublishEventsURL string `mapstructure:"events-url"`
 
+	EventsHeartbeat int `mapstructure:"events-heartbeat"` // node status heartbeat interval in seconds, 0 to disable
+
 	StartMining bool `mapstructure:"start-mining"`
 
 	AtxsPerBlock int `mapstructure:"atxs-per-block"`
 
 	TxsPerBlock int `mapstructure:"txs-per-block"`
 
+	SkipEmptyBlocks bool `mapstructure:"skip-empty-blocks"`
+
+	MaxBlockBytes int `mapstructure:"max-block-bytes"`
+
 	BlockCacheSize int `mapstructure:"block-cache-size"`
 
 	AlwaysListen bool `mapstructure:"always-listen"` // force gossip to always be on (for testing)
@@ -221,3 +221,171 @@ func TestReportNodeStatus(t *testing.T) {
 	CloseEventReporter()
 	ReportNodeStatusUpdate()
 }
+
+func TestReportBlockIncludedAndOrphaned(t *testing.T) {
+	r := require.New(t)
+	included := types.NewExistingBlock(5, []byte("included"), nil)
+	orphaned := types.NewExistingBlock(6, []byte("orphaned"), nil)
+
+	// There should be no error reporting an event before initializing the reporter
+	ReportBlockIncluded(included.ID(), included.LayerIndex)
+	ReportBlockOrphaned(orphaned.ID(), orphaned.LayerIndex)
+	r.Nil(GetBlockIncludedChannel())
+	r.Nil(GetBlockOrphanedChannel())
+
+	r.NoError(InitializeEventReporterWithOptions("", 1, false))
+	defer CloseEventReporter()
+
+	ReportBlockIncluded(included.ID(), included.LayerIndex)
+	ReportBlockOrphaned(orphaned.ID(), orphaned.LayerIndex)
+
+	r.Equal(BlockIncluded{ID: included.ID().String(), Layer: 5}, <-GetBlockIncludedChannel())
+	r.Equal(BlockOrphaned{ID: orphaned.ID().String(), Layer: 6}, <-GetBlockOrphanedChannel())
+}
+
+func TestReportSyncProgress(t *testing.T) {
+	r := require.New(t)
+
+	progress := newSyncProgress(25, 100, 5)
+	r.Equal(25.0, progress.Percentage)
+	r.Equal(15*time.Second, progress.ETA)
+
+	// unknown rate has no ETA
+	progress = newSyncProgress(50, 200, 0)
+	r.Equal(25.0, progress.Percentage)
+	r.Equal(time.Duration(0), progress.ETA)
+
+	progress = newSyncProgress(100, 100, 5)
+	r.Equal(100.0, progress.Percentage)
+	r.Equal(time.Duration(0), progress.ETA)
+
+	// There should be no error reporting an event before initializing the reporter
+	ReportSyncProgress(10, 40, 2)
+	r.Nil(GetSyncProgressChannel())
+
+	r.NoError(InitializeEventReporterWithOptions("", 1, false))
+	defer CloseEventReporter()
+
+	ReportSyncProgress(10, 40, 2)
+	r.Equal(SyncProgress{Synced: 10, Target: 40, Percentage: 25, ETA: 15 * time.Second}, <-GetSyncProgressChannel())
+}
+
+func TestReportNodeStatusHeartbeat(t *testing.T) {
+	r := require.New(t)
+	interval := 10 * time.Millisecond
+
+	r.NoError(InitializeEventReporterWithHeartbeat("", interval))
+	stream := GetStatusChannel()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-stream:
+		case <-time.After(100 * interval):
+			r.FailNow("timed out waiting for heartbeat")
+		}
+	}
+	CloseEventReporter()
+
+	// a reporter without heartbeat should not receive any status updates from the stopped heartbeat
+	r.NoError(InitializeEventReporterWithOptions("", 1, false))
+	defer CloseEventReporter()
+	select {
+	case <-GetStatusChannel():
+		r.FailNow("heartbeat was not stopped")
+	case <-time.After(10 * interval):
+	}
+}
+
+func TestRewardReceivedEvent(t *testing.T) {
+	url := "tcp://localhost:56566"
+
+	InitializeEventPubsub(url)
+	defer CloseEventPubSub()
+
+	s, err := NewSubscriber(url)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+	c, err := s.Subscribe(EventRewardReceived)
+	require.NoError(t, err)
+	s.StartListening()
+	time.Sleep(5 * time.Second)
+
+	ReportRewardReceived(Reward{
+		Layer:    7,
+		Total:    100,
+		Coinbase: addr1,
+		Type:     RewardTypeBlock,
+	})
+
+	select {
+	case <-time.After(7 * time.Second):
+		assert.Fail(t, "didnt receive message")
+	case rec := <-c:
+		e := RewardReceived{}
+		require.NoError(t, types.BytesToInterface(rec[1:], &e))
+		assert.Equal(t, RewardReceived{Coinbase: addr1.String(), Amount: 100, Layer: 7, Type: RewardTypeBlock}, e)
+	}
+}
+
+func TestSubscribeActivationsForCoinbase(t *testing.T) {
+	r := require.New(t)
+	types.SetLayersPerEpoch(3)
+	coinbase1 := types.HexToAddress("aaaa")
+	coinbase2 := types.HexToAddress("bbbb")
+	newAtx := func(coinbase types.Address, sequence uint64) *types.ActivationTx {
+		challenge := types.NIPSTChallenge{NodeID: types.NodeID{Key: "aaaa"}, Sequence: sequence}
+		return types.NewActivationTx(challenge, coinbase, &types.NIPST{}, nil)
+	}
+	atx1 := newAtx(coinbase1, 1)
+	atx2 := newAtx(coinbase2, 2)
+	atx3 := newAtx(coinbase1, 3)
+
+	stream, cancel := SubscribeActivationsForCoinbase(coinbase1)
+	r.Nil(stream)
+	cancel()
+
+	r.NoError(InitializeEventReporterWithOptions("", 3, false))
+	stream, cancel = SubscribeActivationsForCoinbase(coinbase1)
+	r.NotNil(stream)
+	canceled, cancelCanceled := SubscribeActivationsForCoinbase(coinbase1)
+	cancelCanceled()
+	_, ok := <-canceled
+	r.False(ok)
+	cancelCanceled()
+
+	ReportNewActivation(atx1)
+	ReportNewActivation(atx2)
+	ReportNewActivation(atx3)
+	CloseEventReporter()
+	cancel()
+
+	var received []*types.ActivationTx
+	for atx := range stream {
+		received = append(received, atx)
+	}
+	r.Equal([]*types.ActivationTx{atx1, atx3}, received)
+}
+
+func TestReportEpochStarted(t *testing.T) {
+	r := require.New(t)
+	types.SetLayersPerEpoch(3)
+
+	r.NoError(InitializeEventReporterWithOptions("", 2, false))
+	defer CloseEventReporter()
+
+	layerCh := make(chan types.LayerID)
+	SubscribeToLayers(layerCh)
+	// the first tick is in the middle of epoch 1, the boundary to epoch 2 is at layer 6
+	for _, layer := range []types.LayerID{4, 5, 6, 7, 8} {
+		layerCh <- layer
+	}
+
+	stream := GetEpochStartedChannel()
+	r.Equal(EpochStarted{Epoch: 2, FirstLayer: 6}, <-stream)
+	select {
+	case started := <-stream:
+		r.FailNow("unexpected epoch started event", "%v", started)
+	default:
+	}
+}
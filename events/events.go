@@ -17,6 +17,8 @@ const (
 	EventRewardReceived
 	EventCreatedBlock
 	EventCreatedAtx
+	EventBlockIncluded
+	EventBlockOrphaned
 )
 
 // publisher is the event publisher singleton.
@@ -121,6 +123,28 @@ func (ValidBlock) GetChannel() ChannelID {
 	return EventBlockValid
 }
 
+// BlockIncluded signals that the tortoise decided block with id ID is part of the canonical mesh
+type BlockIncluded struct {
+	ID    string
+	Layer uint64
+}
+
+// GetChannel gets the message type which means on which this message should be sent
+func (BlockIncluded) GetChannel() ChannelID {
+	return EventBlockIncluded
+}
+
+// BlockOrphaned signals that the tortoise decided block with id ID is not part of the canonical mesh
+type BlockOrphaned struct {
+	ID    string
+	Layer uint64
+}
+
+// GetChannel gets the message type which means on which this message should be sent
+func (BlockOrphaned) GetChannel() ChannelID {
+	return EventBlockOrphaned
+}
+
 // NewAtx signals that a new ATX has been received
 type NewAtx struct {
 	ID      string
@@ -172,6 +196,8 @@ func (ValidTx) GetChannel() ChannelID {
 type RewardReceived struct {
 	Coinbase string
 	Amount   uint64
+	Layer    uint64
+	Type     string
 }
 
 // GetChannel gets the message type which means on which this message should be sent
@@ -8,6 +8,7 @@ import (
 	"github.com/spacemeshos/go-spacemesh/timesync"
 	"go.uber.org/zap/zapcore"
 	"sync"
+	"time"
 )
 
 // reporter is the event reporter singleton.
@@ -87,6 +88,21 @@ func ReportNewActivation(activation *types.ActivationTx) {
 				log.With().Debug("not reporting activation as no one is listening", activation.Fields(len(innerBytes))...)
 			}
 		}
+		for _, sub := range reporter.coinbaseSubs {
+			if sub.coinbase != activation.Coinbase {
+				continue
+			}
+			if reporter.blocking {
+				sub.channel <- activation
+			} else {
+				select {
+				case sub.channel <- activation:
+				default:
+					log.With().Debug("not reporting activation to coinbase subscriber as no one is listening",
+						log.String("coinbase", sub.coinbase.Short()))
+				}
+			}
+		}
 	}
 }
 
@@ -98,6 +114,8 @@ func ReportRewardReceived(r Reward) {
 	Publish(RewardReceived{
 		Coinbase: r.Coinbase.String(),
 		Amount:   r.Total,
+		Layer:    uint64(r.Layer),
+		Type:     r.Type,
 	})
 
 	if reporter != nil {
@@ -132,6 +150,58 @@ func ReportValidBlock(blockID types.BlockID, valid bool) {
 	})
 }
 
+// ReportBlockIncluded reports a block that the tortoise decided is part of the canonical mesh
+func ReportBlockIncluded(blockID types.BlockID, layer types.LayerID) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	event := BlockIncluded{
+		ID:    blockID.String(),
+		Layer: uint64(layer),
+	}
+	Publish(event)
+
+	if reporter != nil {
+		if reporter.blocking {
+			reporter.channelBlockIncluded <- event
+			log.Debug("reported included block: %v", event)
+		} else {
+			select {
+			case reporter.channelBlockIncluded <- event:
+				log.Debug("reported included block: %v", event)
+			default:
+				log.Debug("not reporting included block as no one is listening: %v", event)
+			}
+		}
+	}
+}
+
+// ReportBlockOrphaned reports a block that the tortoise decided is not part of the canonical mesh
+func ReportBlockOrphaned(blockID types.BlockID, layer types.LayerID) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	event := BlockOrphaned{
+		ID:    blockID.String(),
+		Layer: uint64(layer),
+	}
+	Publish(event)
+
+	if reporter != nil {
+		if reporter.blocking {
+			reporter.channelBlockOrphaned <- event
+			log.Debug("reported orphaned block: %v", event)
+		} else {
+			select {
+			case reporter.channelBlockOrphaned <- event:
+				log.Debug("reported orphaned block: %v", event)
+			default:
+				log.Debug("not reporting orphaned block as no one is listening: %v", event)
+			}
+		}
+	}
+}
+
 // ReportAtxCreated reports a created activation
 func ReportAtxCreated(created bool, layer uint64, id string) {
 	Publish(AtxCreated{Created: created, Layer: layer, ID: id})
@@ -222,6 +292,70 @@ func ReportNodeStatusUpdate() {
 	}
 }
 
+// ReportSyncProgress reports the progress of syncing layers towards the target layer. rate is the number of layers
+// synced per second and is used to estimate the remaining time.
+func ReportSyncProgress(synced, target types.LayerID, rate float64) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		progress := newSyncProgress(synced, target, rate)
+		if reporter.blocking {
+			reporter.channelSyncProgress <- progress
+			log.Debug("reported sync progress: %v", progress)
+		} else {
+			select {
+			case reporter.channelSyncProgress <- progress:
+				log.Debug("reported sync progress: %v", progress)
+			default:
+				log.Debug("not reporting sync progress as no one is listening: %v", progress)
+			}
+		}
+	}
+}
+
+// ReportMempoolState reports the number of pending transactions in the mempool and their total size in bytes
+func ReportMempoolState(pending int, bytes uint64) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		state := MempoolState{Pending: pending, Bytes: bytes}
+		if reporter.blocking {
+			reporter.channelMempoolState <- state
+			log.Debug("reported mempool state: %v", state)
+		} else {
+			select {
+			case reporter.channelMempoolState <- state:
+				log.Debug("reported mempool state: %v", state)
+			default:
+				log.Debug("not reporting mempool state as no one is listening: %v", state)
+			}
+		}
+	}
+}
+
+// ReportEpochStarted reports that a new epoch has begun
+func ReportEpochStarted(epoch types.EpochID, firstLayer types.LayerID) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		started := EpochStarted{Epoch: epoch, FirstLayer: firstLayer}
+		if reporter.blocking {
+			reporter.channelEpoch <- started
+			log.With().Debug("reported epoch started", epoch, firstLayer)
+		} else {
+			select {
+			case reporter.channelEpoch <- started:
+				log.With().Debug("reported epoch started", epoch, firstLayer)
+			default:
+				log.With().Debug("not reporting epoch started as no one is listening", epoch, firstLayer)
+			}
+		}
+	}
+}
+
 // ReportReceipt reports creation or receipt of a new tx receipt
 func ReportReceipt(r TxReceipt) {
 	mu.RLock()
@@ -284,6 +418,40 @@ func GetActivationsChannel() chan *types.ActivationTx {
 	return nil
 }
 
+// SubscribeActivationsForCoinbase returns a channel of activations whose coinbase is addr, and a function that cancels
+// the subscription. The channel is closed when the subscription is canceled or by CloseEventReporter.
+func SubscribeActivationsForCoinbase(addr types.Address) (<-chan *types.ActivationTx, func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if reporter == nil {
+		return nil, func() {}
+	}
+	r := reporter
+	sub := coinbaseActivationSub{
+		coinbase: addr,
+		channel:  make(chan *types.ActivationTx, r.bufsize),
+	}
+	r.coinbaseSubs = append(r.coinbaseSubs, sub)
+	cancel := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// once the reporter is closed, so are all of its subscriptions
+		if reporter != r {
+			return
+		}
+		for i, s := range r.coinbaseSubs {
+			if s.channel == sub.channel {
+				r.coinbaseSubs = append(r.coinbaseSubs[:i], r.coinbaseSubs[i+1:]...)
+				close(s.channel)
+				return
+			}
+		}
+	}
+	return sub.channel, cancel
+}
+
 // GetLayerChannel returns a channel of all layer data
 func GetLayerChannel() chan NewLayer {
 	mu.RLock()
@@ -350,6 +518,61 @@ func GetReceiptChannel() chan TxReceipt {
 	return nil
 }
 
+// GetEpochStartedChannel returns a channel for epoch start notifications
+func GetEpochStartedChannel() chan EpochStarted {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		return reporter.channelEpoch
+	}
+	return nil
+}
+
+// GetMempoolStateChannel returns a channel for mempool state updates
+func GetMempoolStateChannel() chan MempoolState {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		return reporter.channelMempoolState
+	}
+	return nil
+}
+
+// GetSyncProgressChannel returns a channel for sync progress updates
+func GetSyncProgressChannel() chan SyncProgress {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		return reporter.channelSyncProgress
+	}
+	return nil
+}
+
+// GetBlockIncludedChannel returns a channel for blocks included in the canonical mesh
+func GetBlockIncludedChannel() chan BlockIncluded {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		return reporter.channelBlockIncluded
+	}
+	return nil
+}
+
+// GetBlockOrphanedChannel returns a channel for blocks orphaned by the tortoise
+func GetBlockOrphanedChannel() chan BlockOrphaned {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if reporter != nil {
+		return reporter.channelBlockOrphaned
+	}
+	return nil
+}
+
 // InitializeEventReporter initializes the event reporting interface
 func InitializeEventReporter(url string) error {
 	// By default use zero-buffer channels and non-blocking.
@@ -362,6 +585,26 @@ func InitializeEventReporter(url string) error {
 func InitializeEventReporterWithOptions(url string, bufsize int, blocking bool) error {
 	mu.Lock()
 	defer mu.Unlock()
+	return initializeEventReporter(url, bufsize, blocking)
+}
+
+// InitializeEventReporterWithHeartbeat initializes the event reporting interface and additionally reports a node
+// status update every interval, so that listeners keep hearing from a node even when it has no other activity.
+// A non-positive interval disables the heartbeat. The heartbeat stops when CloseEventReporter is called.
+func InitializeEventReporterWithHeartbeat(url string, interval time.Duration) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := initializeEventReporter(url, 0, false); err != nil {
+		return err
+	}
+	if interval > 0 {
+		go heartbeat(interval, reporter.stopChan)
+	}
+	return nil
+}
+
+// initializeEventReporter must be called while holding mu.
+func initializeEventReporter(url string, bufsize int, blocking bool) error {
 	if reporter != nil {
 		return errors.New("reporter is already initialized, call CloseEventReporter before reinitializing")
 	}
@@ -372,6 +615,19 @@ func InitializeEventReporterWithOptions(url string, bufsize int, blocking bool)
 	return nil
 }
 
+func heartbeat(interval time.Duration, stopChan chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ReportNodeStatusUpdate()
+		case <-stopChan:
+			return
+		}
+	}
+}
+
 // SubscribeToLayers is used to track and report automatically every time a
 // new layer is reached.
 func SubscribeToLayers(newLayerCh timesync.LayerTimer) {
@@ -379,14 +635,24 @@ func SubscribeToLayers(newLayerCh timesync.LayerTimer) {
 	defer mu.RUnlock()
 
 	if reporter != nil {
+		stopChan := reporter.stopChan
 		// This will block, so run in a goroutine
 		go func() {
+			var lastEpoch types.EpochID
+			ticked := false
 			for {
 				select {
 				case layer := <-newLayerCh:
 					log.With().Debug("reporter got new layer", layer)
 					ReportNodeStatusUpdate()
-				case <-reporter.stopChan:
+
+					// report the epoch once, on the first tick that moves into it
+					epoch := layer.GetEpoch()
+					if (ticked && epoch > lastEpoch) || (!ticked && layer == epoch.FirstLayer()) {
+						ReportEpochStarted(epoch, epoch.FirstLayer())
+					}
+					lastEpoch, ticked = epoch, true
+				case <-stopChan:
 					return
 				}
 			}
@@ -433,50 +699,105 @@ type TxReceipt struct {
 	Address types.Address
 }
 
+// RewardTypeBlock is the type of a reward for a block: its share of the layer reward and of the layer's tx fees
+const RewardTypeBlock = "block"
+
 // Reward represents a reward object with extra data needed by the API
 type Reward struct {
 	Layer       types.LayerID
 	Total       uint64
 	LayerReward uint64
 	Coinbase    types.Address
+	Type        string
 	// TODO: We don't currently have a way to get these two.
 	// See https://github.com/spacemeshos/go-spacemesh/issues/2068
 	//LayerComputed
 	//Smesher
 }
 
+// SyncProgress represents how far the node got in syncing layers, along with a rough estimate of the remaining time
+type SyncProgress struct {
+	Synced     types.LayerID
+	Target     types.LayerID
+	Percentage float64
+	// ETA is zero when the sync rate is unknown
+	ETA time.Duration
+}
+
+func newSyncProgress(synced, target types.LayerID, rate float64) SyncProgress {
+	progress := SyncProgress{Synced: synced, Target: target, Percentage: 100}
+	if synced >= target {
+		return progress
+	}
+	progress.Percentage = 100 * float64(synced) / float64(target)
+	if rate > 0 {
+		progress.ETA = time.Duration(float64(target-synced) / rate * float64(time.Second))
+	}
+	return progress
+}
+
+// EpochStarted represents the beginning of an epoch
+type EpochStarted struct {
+	Epoch      types.EpochID
+	FirstLayer types.LayerID
+}
+
+// MempoolState represents the occupancy of the mempool
+type MempoolState struct {
+	Pending int
+	Bytes   uint64
+}
+
 // TransactionWithValidity wraps a tx with its validity info
 type TransactionWithValidity struct {
 	Transaction *types.Transaction
 	Valid       bool
 }
 
+type coinbaseActivationSub struct {
+	coinbase types.Address
+	channel  chan *types.ActivationTx
+}
+
 // EventReporter is the struct that receives incoming events and dispatches them
 type EventReporter struct {
-	channelTransaction chan TransactionWithValidity
-	channelActivation  chan *types.ActivationTx
-	channelLayer       chan NewLayer
-	channelError       chan NodeError
-	channelStatus      chan struct{}
-	channelAccount     chan types.Address
-	channelReward      chan Reward
-	channelReceipt     chan TxReceipt
-	stopChan           chan struct{}
-	blocking           bool
+	channelTransaction   chan TransactionWithValidity
+	channelActivation    chan *types.ActivationTx
+	channelLayer         chan NewLayer
+	channelError         chan NodeError
+	channelStatus        chan struct{}
+	channelAccount       chan types.Address
+	channelReward        chan Reward
+	channelReceipt       chan TxReceipt
+	channelSyncProgress  chan SyncProgress
+	channelMempoolState  chan MempoolState
+	channelEpoch         chan EpochStarted
+	channelBlockIncluded chan BlockIncluded
+	channelBlockOrphaned chan BlockOrphaned
+	coinbaseSubs         []coinbaseActivationSub
+	stopChan             chan struct{}
+	blocking             bool
+	bufsize              int
 }
 
 func newEventReporter(bufsize int, blocking bool) *EventReporter {
 	return &EventReporter{
-		channelTransaction: make(chan TransactionWithValidity, bufsize),
-		channelActivation:  make(chan *types.ActivationTx, bufsize),
-		channelLayer:       make(chan NewLayer, bufsize),
-		channelStatus:      make(chan struct{}, bufsize),
-		channelAccount:     make(chan types.Address, bufsize),
-		channelReward:      make(chan Reward, bufsize),
-		channelReceipt:     make(chan TxReceipt, bufsize),
-		channelError:       make(chan NodeError, bufsize),
-		stopChan:           make(chan struct{}),
-		blocking:           blocking,
+		channelTransaction:   make(chan TransactionWithValidity, bufsize),
+		channelActivation:    make(chan *types.ActivationTx, bufsize),
+		channelLayer:         make(chan NewLayer, bufsize),
+		channelStatus:        make(chan struct{}, bufsize),
+		channelAccount:       make(chan types.Address, bufsize),
+		channelReward:        make(chan Reward, bufsize),
+		channelReceipt:       make(chan TxReceipt, bufsize),
+		channelError:         make(chan NodeError, bufsize),
+		channelSyncProgress:  make(chan SyncProgress, bufsize),
+		channelMempoolState:  make(chan MempoolState, bufsize),
+		channelEpoch:         make(chan EpochStarted, bufsize),
+		channelBlockIncluded: make(chan BlockIncluded, bufsize),
+		channelBlockOrphaned: make(chan BlockOrphaned, bufsize),
+		stopChan:             make(chan struct{}),
+		blocking:             blocking,
+		bufsize:              bufsize,
 	}
 }
 
@@ -493,6 +814,14 @@ func CloseEventReporter() {
 		close(reporter.channelAccount)
 		close(reporter.channelReward)
 		close(reporter.channelReceipt)
+		close(reporter.channelSyncProgress)
+		close(reporter.channelMempoolState)
+		close(reporter.channelEpoch)
+		close(reporter.channelBlockIncluded)
+		close(reporter.channelBlockOrphaned)
+		for _, sub := range reporter.coinbaseSubs {
+			close(sub.channel)
+		}
 		close(reporter.stopChan)
 		reporter = nil
 	}
@@ -2,17 +2,21 @@
 package miner
 
 import (
+	"bytes"
+	"container/heap"
 	"crypto/md5"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"github.com/spacemeshos/go-spacemesh/blocks"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/common/util"
 	"github.com/spacemeshos/go-spacemesh/database"
 	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/p2p"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
@@ -24,6 +28,24 @@ const defaultFee = 1
 // AtxsPerBlockLimit indicates the maximum number of atxs a block can reference
 const AtxsPerBlockLimit = 100
 
+// includedTxsLayers is the number of recent layers for which the builder remembers the txs it included in its blocks,
+// so it doesn't include them again while they're still in the mempool
+const includedTxsLayers = 3
+
+// ErrEmptyBlock is returned by createBlock when the builder is configured not to produce empty blocks and there's
+// nothing to put in the block
+var ErrEmptyBlock = errors.New("block has no transactions")
+
+// Reasons passed to the OnLayerSkipped callback
+const (
+	SkipReasonNotSynced         = "not synced"
+	SkipReasonGenesis           = "genesis"
+	SkipReasonNoATX             = "no ATX"
+	SkipReasonEligibilityFailed = "failed to check for block eligibility"
+	SkipReasonNotEligible       = "not eligible"
+	SkipReasonEmptyBlock        = "no txs to include"
+)
+
 type signer interface {
 	Sign(m []byte) []byte
 }
@@ -70,6 +92,7 @@ type BlockBuilder struct {
 	AtxDb           atxDb
 	TransactionPool txPool
 	mu              sync.Mutex
+	loop            sync.WaitGroup
 	network         p2p.Service
 	weakCoinToss    weakCoinProvider
 	meshProvider    meshProvider
@@ -82,6 +105,10 @@ type BlockBuilder struct {
 	projector       projector
 	db              database.Database
 	layerPerEpoch   uint16
+	includedTxs     map[types.LayerID][]types.TransactionID // txs included in our blocks in recent layers
+	skipEmpty       bool                                    // whether to skip blocks without txs
+	maxBlockBytes   int                                     // max size of a serialized block, 0 means no limit
+	layerSkipped    func(layer types.LayerID, reason string)
 }
 
 // Config is the block builders configuration struct
@@ -91,12 +118,22 @@ type Config struct {
 	AtxsPerBlock   int
 	LayersPerEpoch uint16
 	TxsPerBlock    int
+	// SkipEmptyBlocks makes the builder skip blocks that have no txs to include, unless they have to carry the
+	// epoch's active set.
+	SkipEmptyBlocks bool
+	// MaxBlockBytes caps the size of a serialized block by dropping txs from it. Zero means no limit.
+	MaxBlockBytes int
+	// Seed seeds the builder's random source. Nil means the seed is derived from the MinerID.
+	Seed *int64
 }
 
 // NewBlockBuilder creates a struct of block builder type.
 func NewBlockBuilder(config Config, sgn signer, net p2p.Service, beginRoundEvent chan types.LayerID, weakCoin weakCoinProvider, orph meshProvider, hare hareResultProvider, blockOracle blockOracle, syncer syncer, projector projector, txPool txPool, atxDB atxDb, lg log.Log) *BlockBuilder {
 
-	seed := binary.BigEndian.Uint64(md5.New().Sum([]byte(config.MinerID.Key)))
+	seed := int64(binary.BigEndian.Uint64(md5.New().Sum([]byte(config.MinerID.Key))))
+	if config.Seed != nil {
+		seed = *config.Seed
+	}
 
 	db, err := database.Create("builder", 16, 16, lg)
 	if err != nil {
@@ -108,7 +145,7 @@ func NewBlockBuilder(config Config, sgn signer, net p2p.Service, beginRoundEvent
 		signer:          sgn,
 		hdist:           types.LayerID(config.Hdist),
 		Log:             lg,
-		rnd:             rand.New(rand.NewSource(int64(seed))),
+		rnd:             rand.New(rand.NewSource(seed)),
 		beginRoundEvent: beginRoundEvent,
 		stopChan:        make(chan struct{}),
 		hareResult:      hare,
@@ -126,6 +163,9 @@ func NewBlockBuilder(config Config, sgn signer, net p2p.Service, beginRoundEvent
 		TransactionPool: txPool,
 		db:              db,
 		layerPerEpoch:   config.LayersPerEpoch,
+		includedTxs:     make(map[types.LayerID][]types.TransactionID),
+		skipEmpty:       config.SkipEmptyBlocks,
+		maxBlockBytes:   config.MaxBlockBytes,
 	}
 
 }
@@ -140,20 +180,39 @@ func (t *BlockBuilder) Start() error {
 	}
 
 	t.started = true
-	go t.createBlockLoop()
+	t.loop.Add(1)
+	go func() {
+		defer t.loop.Done()
+		t.createBlockLoop()
+	}()
 	return nil
 }
 
-// Close stops listeners and stops trying to create block in layers
+// OnLayerSkipped sets a callback that's invoked with the reason whenever the builder doesn't try to create blocks in a
+// layer. It must be called before Start.
+func (t *BlockBuilder) OnLayerSkipped(fn func(layer types.LayerID, reason string)) {
+	t.layerSkipped = fn
+}
+
+func (t *BlockBuilder) skipLayer(layer types.LayerID, reason string) {
+	if t.layerSkipped != nil {
+		t.layerSkipped(layer, reason)
+	}
+}
+
+// Close stops listeners and stops trying to create block in layers. It waits for the block being created, if any, so
+// that its ref block is persisted before the DB is closed.
 func (t *BlockBuilder) Close() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	t.db.Close()
 	if !t.started {
+		t.db.Close()
 		return fmt.Errorf("already stopped")
 	}
 	t.started = false
 	close(t.stopChan)
+	t.loop.Wait()
+	t.db.Close()
 	return nil
 }
 
@@ -295,15 +354,35 @@ func (t *BlockBuilder) createBlock(id types.LayerID, atxID types.ATXID, eligibil
 		atxs := activeSet
 		b.ActiveSet = &atxs
 	} else {
+		if len(txids) == 0 && t.skipEmpty {
+			return nil, ErrEmptyBlock
+		}
 		b.RefBlock = &refBlock
 	}
 
-	blockBytes, err := types.InterfaceToBytes(b)
+	bl, err := t.signBlock(b)
 	if err != nil {
 		return nil, err
 	}
-
-	bl := &types.Block{MiniBlock: b, Signature: t.signer.Sign(blockBytes)}
+	for t.maxBlockBytes > 0 {
+		blockBytes, err := types.InterfaceToBytes(bl)
+		if err != nil {
+			return nil, err
+		}
+		excess := len(blockBytes) - t.maxBlockBytes
+		if excess <= 0 {
+			break
+		}
+		if len(b.TxIDs) == 0 {
+			return nil, fmt.Errorf("block size %v exceeds max block size %v", len(blockBytes), t.maxBlockBytes)
+		}
+		// txs are ordered by priority, so the ones at the end are dropped first
+		drop := util.Min(len(b.TxIDs), (excess+len(types.TransactionID{})-1)/len(types.TransactionID{}))
+		b.TxIDs = b.TxIDs[:len(b.TxIDs)-drop]
+		if bl, err = t.signBlock(b); err != nil {
+			return nil, err
+		}
+	}
 
 	bl.Initialize()
 
@@ -330,7 +409,16 @@ func (t *BlockBuilder) createBlock(id types.LayerID, atxID types.ATXID, eligibil
 	return bl, nil
 }
 
-func selectAtxs(atxs []types.ATXID, atxsPerBlock int) []types.ATXID {
+func (t *BlockBuilder) signBlock(b types.MiniBlock) (*types.Block, error) {
+	blockBytes, err := types.InterfaceToBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Block{MiniBlock: b, Signature: t.signer.Sign(blockBytes)}, nil
+}
+
+// selectAtxs isn't used by createBlockLoop yet, so blocks currently carry the full active set.
+func (t *BlockBuilder) selectAtxs(atxs []types.ATXID, atxsPerBlock int) []types.ATXID {
 	if len(atxs) == 0 { // no atxs to pick from
 		return atxs
 	}
@@ -342,7 +430,7 @@ func selectAtxs(atxs []types.ATXID, atxsPerBlock int) []types.ATXID {
 	// we have more than atxsPerBlock, choose randomly
 	selected := make([]types.ATXID, 0)
 	for i := 0; i < atxsPerBlock; i++ {
-		idx := i + rand.Intn(len(atxs)-i)       // random index in [i, len(atxs))
+		idx := i + t.rnd.Intn(len(atxs)-i)      // random index in [i, len(atxs))
 		selected = append(selected, atxs[idx])  // select atx at idx
 		atxs[i], atxs[idx] = atxs[idx], atxs[i] // swap selected with i so we don't choose it again
 	}
@@ -350,6 +438,125 @@ func selectAtxs(atxs []types.ATXID, atxsPerBlock int) []types.ATXID {
 	return selected
 }
 
+// accountTxs holds the pending txs of a single account, in nonce order.
+type accountTxs []*types.Transaction
+
+// txsByFee is a heap of accounts, ordered by the fee of each account's next tx. Equal fees are ordered by tx ID so
+// that the selection is deterministic.
+type txsByFee []accountTxs
+
+func (h txsByFee) Len() int { return len(h) }
+
+func (h txsByFee) Less(i, j int) bool {
+	if h[i][0].Fee != h[j][0].Fee {
+		return h[i][0].Fee > h[j][0].Fee
+	}
+	idI, idJ := h[i][0].ID(), h[j][0].ID()
+	return bytes.Compare(idI[:], idJ[:]) < 0
+}
+
+func (h txsByFee) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *txsByFee) Push(x interface{}) { *h = append(*h, x.(accountTxs)) }
+
+func (h *txsByFee) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// selectTxsByFee picks up to txsPerBlock transactions, preferring those with the highest fee. txs must list each
+// account's txs in nonce order, as returned by the mempool. Only an account's next tx competes on fee, so the txs
+// selected for each account always form a gap-free nonce sequence.
+func selectTxsByFee(txs []*types.Transaction, txsPerBlock int) []types.TransactionID {
+	accounts := make(map[types.Address]int)
+	var h txsByFee
+	for _, tx := range txs {
+		idx, found := accounts[tx.Origin()]
+		if !found {
+			idx = len(h)
+			accounts[tx.Origin()] = idx
+			h = append(h, nil)
+		}
+		h[idx] = append(h[idx], tx)
+	}
+	heap.Init(&h)
+
+	var txIDs []types.TransactionID
+	for h.Len() > 0 && len(txIDs) < txsPerBlock {
+		txIDs = append(txIDs, h[0][0].ID())
+		if len(h[0]) > 1 {
+			h[0] = h[0][1:]
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	return txIDs
+}
+
+// selectTxs fetches all transactions that are expected to be valid from the mempool and selects the ones to include
+// in a block, so that the highest paying transactions are picked when the pool exceeds the block capacity.
+// Transactions that were recently included in one of our blocks are skipped.
+func (t *BlockBuilder) selectTxs() ([]types.TransactionID, error) {
+	_, txs, err := t.TransactionPool.GetTxsForBlock(math.MaxInt32, t.projector.GetProjection)
+	if err != nil {
+		return nil, err
+	}
+	return selectTxsByFee(t.filterInvalidTxs(t.filterIncludedTxs(txs)), t.txsPerBlock), nil
+}
+
+// filterInvalidTxs drops txs that fail validation, in case they reached the pool without being validated. An account's
+// txs that follow an invalid one are dropped as well, since they can't be applied without it.
+func (t *BlockBuilder) filterInvalidTxs(txs []*types.Transaction) []*types.Transaction {
+	invalid := make(map[types.Address]struct{})
+	var filtered []*types.Transaction
+	for _, tx := range txs {
+		if _, found := invalid[tx.Origin()]; found {
+			continue
+		}
+		if err := tx.Validate(); err != nil {
+			t.With().Warning("not selecting invalid tx", tx.ID(), log.Err(err))
+			invalid[tx.Origin()] = struct{}{}
+			continue
+		}
+		filtered = append(filtered, tx)
+	}
+	return filtered
+}
+
+func (t *BlockBuilder) filterIncludedTxs(txs []*types.Transaction) []*types.Transaction {
+	if len(t.includedTxs) == 0 {
+		return txs
+	}
+	included := make(map[types.TransactionID]struct{})
+	for _, ids := range t.includedTxs {
+		for _, id := range ids {
+			included[id] = struct{}{}
+		}
+	}
+	var filtered []*types.Transaction
+	for _, tx := range txs {
+		if _, found := included[tx.ID()]; !found {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
+
+// markTxsIncluded records the txs included in a block we created in layer, and forgets txs included more than
+// includedTxsLayers layers before it.
+func (t *BlockBuilder) markTxsIncluded(layer types.LayerID, txIDs []types.TransactionID) {
+	t.includedTxs[layer] = append(t.includedTxs[layer], txIDs...)
+	for l := range t.includedTxs {
+		if l+includedTxsLayers <= layer {
+			delete(t.includedTxs, l)
+		}
+	}
+}
+
 func (t *BlockBuilder) createBlockLoop() {
 	for {
 		select {
@@ -360,32 +567,57 @@ func (t *BlockBuilder) createBlockLoop() {
 		case layerID := <-t.beginRoundEvent:
 			if !t.syncer.IsSynced() {
 				t.Debug("builder got layer %v not synced yet", layerID)
+				t.skipLayer(layerID, SkipReasonNotSynced)
 				continue
 			}
 
 			t.Debug("builder got layer %v", layerID)
+			if layerID.GetEpoch().IsGenesis() {
+				events.ReportDoneCreatingBlock(false, uint64(layerID), "")
+				t.With().Info("Notice: no blocks are created in genesis layers", layerID)
+				t.skipLayer(layerID, SkipReasonGenesis)
+				continue
+			}
 			atxID, proofs, atxs, err := t.blockOracle.BlockEligible(layerID)
+			if err == blocks.ErrNoATX {
+				events.ReportDoneCreatingBlock(true, uint64(layerID), "no ATX for the layer's epoch")
+				t.With().Warning("no ATX for the layer's epoch, cannot create blocks", layerID)
+				t.skipLayer(layerID, SkipReasonNoATX)
+				continue
+			}
 			if err != nil {
 				events.ReportDoneCreatingBlock(true, uint64(layerID), "failed to check for block eligibility")
 				t.With().Error("failed to check for block eligibility", layerID, log.Err(err))
+				t.skipLayer(layerID, SkipReasonEligibilityFailed)
 				continue
 			}
 			if len(proofs) == 0 {
 				events.ReportDoneCreatingBlock(false, uint64(layerID), "")
 				t.With().Info("Notice: not eligible for blocks in layer", layerID)
+				t.skipLayer(layerID, SkipReasonNotEligible)
 				continue
 			}
 			// TODO: include multiple proofs in each block and weigh blocks where applicable
 
-			//reducedAtxList := selectAtxs(atxList, t.atxsPerBlock)
+			//reducedAtxList := t.selectAtxs(atxList, t.atxsPerBlock)
+
+			// one block is created per proof. The first block of the epoch carries the active set and is stored as the
+			// epoch's ref block, which the following blocks reference instead.
+			emptyBlocks := 0
 			for _, eligibilityProof := range proofs {
-				txList, _, err := t.TransactionPool.GetTxsForBlock(t.txsPerBlock, t.projector.GetProjection)
+				txList, err := t.selectTxs()
 				if err != nil {
 					events.ReportDoneCreatingBlock(true, uint64(layerID), "failed to get txs for block")
 					t.With().Error("failed to get txs for block", layerID, log.Err(err))
 					continue
 				}
 				blk, err := t.createBlock(layerID, atxID, eligibilityProof, txList, atxs)
+				if err == ErrEmptyBlock {
+					events.ReportDoneCreatingBlock(true, uint64(layerID), "skipped empty block")
+					t.With().Info("no txs to include, skipping empty block", layerID)
+					emptyBlocks++
+					continue
+				}
 				if err != nil {
 					events.ReportDoneCreatingBlock(true, uint64(layerID), "cannot create new block")
 					t.Error("cannot create new block, %v ", err)
@@ -397,6 +629,7 @@ func (t *BlockBuilder) createBlockLoop() {
 					t.With().Error("failed to store block", blk.ID(), log.Err(err))
 					continue
 				}
+				t.markTxsIncluded(layerID, blk.TxIDs)
 				go func() {
 					bytes, err := types.InterfaceToBytes(blk)
 					if err != nil {
@@ -411,6 +644,9 @@ func (t *BlockBuilder) createBlockLoop() {
 					events.ReportDoneCreatingBlock(true, uint64(layerID), "")
 				}()
 			}
+			if emptyBlocks == len(proofs) {
+				t.skipLayer(layerID, SkipReasonEmptyBlock)
+			}
 		}
 	}
 }
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/spacemeshos/go-spacemesh/blocks"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -44,14 +47,19 @@ func (m MockHare) GetResult(id types.LayerID) ([]types.BlockID, error) {
 }
 
 type mockBlockOracle struct {
-	calls int
-	err   error
-	J     uint32
+	calls     int
+	err       error
+	J         uint32
+	numProofs int // number of proofs to return, 1 if unset
 }
 
 func (mbo *mockBlockOracle) BlockEligible(types.LayerID) (types.ATXID, []types.BlockEligibilityProof, []types.ATXID, error) {
 	mbo.calls++
-	return types.ATXID(types.Hash32{1, 2, 3}), []types.BlockEligibilityProof{{J: mbo.J, Sig: []byte{1}}}, []types.ATXID{atx1, atx2, atx3, atx4, atx5}, mbo.err
+	proofs := []types.BlockEligibilityProof{{J: mbo.J, Sig: []byte{1}}}
+	for i := 1; i < mbo.numProofs; i++ {
+		proofs = append(proofs, types.BlockEligibilityProof{J: mbo.J + uint32(i), Sig: []byte{1}})
+	}
+	return types.ATXID(types.Hash32{1, 2, 3}), proofs, []types.ATXID{atx1, atx2, atx3, atx4, atx5}, mbo.err
 }
 
 type mockAtxValidator struct{}
@@ -124,7 +132,6 @@ func init() {
 var mockProjector = &MockProjector{}
 
 func TestBlockBuilder_StartStop(t *testing.T) {
-	rand.Seed(0)
 	net := service.NewSimulator()
 	n := net.NewNode()
 
@@ -337,6 +344,108 @@ func NewTx(t *testing.T, nonce uint64, recipient types.Address, signer *signing.
 	return tx
 }
 
+func TestBlockBuilder_SelectTxsByFee(t *testing.T) {
+	r := require.New(t)
+	n := service.NewSimulator().NewNode()
+
+	txPool := state.NewTxMemPool()
+	builder := createBlockBuilder("a", n, nil)
+	builder.TransactionPool = txPool
+	builder.txsPerBlock = 3
+
+	recipient := types.BytesToAddress([]byte{0x01})
+	signer := signing.NewEdSigner()
+	fees := []uint64{1, 7, 3, 9, 5}
+	txs := make([]*types.Transaction, len(fees))
+	for i, fee := range fees {
+		tx, err := types.NewSignedTx(uint64(i+1), recipient, 1, defaultGasLimit, fee, signer)
+		r.NoError(err)
+		txPool.Put(tx.ID(), tx)
+		txs[i] = tx
+	}
+	other, err := types.NewSignedTx(1, recipient, 1, defaultGasLimit, 4, signing.NewEdSigner())
+	r.NoError(err)
+	txPool.Put(other.ID(), other)
+
+	// the other account's tx pays more than the first account's next tx, but the first account's txs are selected in
+	// nonce order, so its higher paying txs with later nonces can't skip ahead
+	txIDs, err := builder.selectTxs()
+	r.NoError(err)
+	r.Equal([]types.TransactionID{other.ID(), txs[0].ID(), txs[1].ID()}, txIDs)
+
+	// when the pool does not exceed the cap all transactions are selected
+	builder.txsPerBlock = 10
+	txIDs, err = builder.selectTxs()
+	r.NoError(err)
+	r.Equal([]types.TransactionID{other.ID(), txs[0].ID(), txs[1].ID(), txs[2].ID(), txs[3].ID(), txs[4].ID()}, txIDs)
+}
+
+func TestBlockBuilder_ExcludeIncludedTxs(t *testing.T) {
+	r := require.New(t)
+	n := service.NewSimulator().NewNode()
+
+	txPool := state.NewTxMemPool()
+	builder := createBlockBuilder("a", n, nil)
+	builder.TransactionPool = txPool
+	builder.txsPerBlock = 10
+
+	recipient := types.BytesToAddress([]byte{0x01})
+	signer := signing.NewEdSigner()
+	tx1, err := types.NewSignedTx(1, recipient, 1, defaultGasLimit, 1, signer)
+	r.NoError(err)
+	txPool.Put(tx1.ID(), tx1)
+
+	// the tx is included in a block in layer 5 but remains in the pool
+	txIDs, err := builder.selectTxs()
+	r.NoError(err)
+	r.Equal([]types.TransactionID{tx1.ID()}, txIDs)
+	builder.markTxsIncluded(5, txIDs)
+
+	tx2, err := types.NewSignedTx(2, recipient, 1, defaultGasLimit, 1, signer)
+	r.NoError(err)
+	txPool.Put(tx2.ID(), tx2)
+
+	txIDs, err = builder.selectTxs()
+	r.NoError(err)
+	r.Equal([]types.TransactionID{tx2.ID()}, txIDs)
+	builder.markTxsIncluded(6, txIDs)
+
+	// once layer 5 is old enough its txs are forgotten
+	builder.markTxsIncluded(5+includedTxsLayers, nil)
+	txIDs, err = builder.selectTxs()
+	r.NoError(err)
+	r.Equal([]types.TransactionID{tx1.ID()}, txIDs)
+}
+
+func TestBlockBuilder_ExcludeInvalidTxs(t *testing.T) {
+	r := require.New(t)
+	n := service.NewSimulator().NewNode()
+
+	txPool := state.NewTxMemPool()
+	builder := createBlockBuilder("a", n, nil)
+	builder.TransactionPool = txPool
+	builder.txsPerBlock = 10
+
+	recipient := types.BytesToAddress([]byte{0x01})
+	signer := signing.NewEdSigner()
+	tx1, err := types.NewSignedTx(1, recipient, 1, defaultGasLimit, 1, signer)
+	r.NoError(err)
+	// the fee exceeds the gas limit, and the account's next tx can't be applied without it
+	tx2, err := types.NewSignedTx(2, recipient, 1, 10, 11, signer)
+	r.NoError(err)
+	tx3, err := types.NewSignedTx(3, recipient, 1, defaultGasLimit, 1, signer)
+	r.NoError(err)
+	other, err := types.NewSignedTx(1, recipient, 1, defaultGasLimit, 1, signing.NewEdSigner())
+	r.NoError(err)
+	for _, tx := range []*types.Transaction{tx1, tx2, tx3, other} {
+		txPool.Put(tx.ID(), tx)
+	}
+
+	txIDs, err := builder.selectTxs()
+	r.NoError(err)
+	r.ElementsMatch([]types.TransactionID{tx1.ID(), other.ID()}, txIDs)
+}
+
 func TestBlockBuilder_SerializeTrans(t *testing.T) {
 	tx := NewTx(t, 1, types.BytesToAddress([]byte{0x02}), signing.NewEdSigner())
 	buf, err := types.InterfaceToBytes(tx)
@@ -508,24 +617,24 @@ var (
 
 func Test_selectAtxs(t *testing.T) {
 	r := require.New(t)
+	bb := createBlockBuilder("a", service.NewSimulator().NewNode(), nil)
 
 	atxs := []types.ATXID{atx1, atx2, atx3, atx4, atx5}
-	selected := selectAtxs(atxs, 2)
+	selected := bb.selectAtxs(atxs, 2)
 	r.Equal(2, len(selected))
 
-	selected = selectAtxs(atxs, 5)
+	selected = bb.selectAtxs(atxs, 5)
 	r.Equal(5, len(selected))
 
-	selected = selectAtxs(atxs, 10)
+	selected = bb.selectAtxs(atxs, 10)
 	r.Equal(5, len(selected))
 
 	// check uniformity
-	rand.Seed(1000)
 	origin := []types.ATXID{atx1, atx2, atx3, atx4, atx5}
 	mp := make(map[types.ATXID]struct{}, 0)
 	for i := 0; i < 100; i++ {
 		atxs = []types.ATXID{atx1, atx2, atx3, atx4, atx5}
-		selected = selectAtxs(atxs, 2)
+		selected = bb.selectAtxs(atxs, 2)
 
 		for _, i := range selected {
 			mp[i] = struct{}{}
@@ -548,6 +657,25 @@ func Test_selectAtxs(t *testing.T) {
 	r.Equal(5, len(mp))
 }
 
+func TestBlockBuilder_SelectAtxsSeed(t *testing.T) {
+	r := require.New(t)
+	n := service.NewSimulator().NewNode()
+	seed := int64(0)
+	newBuilder := func(id string) *BlockBuilder {
+		cfg := Config{MinerID: types.NodeID{Key: id}, Hdist: 5, LayersPerEpoch: 3, Seed: &seed}
+		return NewBlockBuilder(cfg, signing.NewEdSigner(), n, make(chan types.LayerID), MockCoin{}, &mockMesh{}, &mockResult{}, &mockBlockOracle{}, &mockSyncer{}, mockProjector, nil, atxDbMock{}, log.NewDefault(t.Name()))
+	}
+	// the builders have different miner IDs, so only the configured seed makes their selections match
+	bb1 := newBuilder("a")
+	bb2 := newBuilder("b")
+
+	for i := 0; i < 10; i++ {
+		selected1 := bb1.selectAtxs([]types.ATXID{atx1, atx2, atx3, atx4, atx5}, 2)
+		selected2 := bb2.selectAtxs([]types.ATXID{atx1, atx2, atx3, atx4, atx5}, 2)
+		r.Equal(selected1, selected2)
+	}
+}
+
 var (
 	b1 = types.NewExistingBlock(1, []byte{1}, nil)
 	b2 = types.NewExistingBlock(1, []byte{2}, nil)
@@ -606,6 +734,16 @@ func (m *mockMesh) AddBlockWithTxs(blk *types.Block) error {
 	return nil
 }
 
+type recordingMesh struct {
+	*mockMesh
+	blocks chan *types.Block
+}
+
+func (m *recordingMesh) AddBlockWithTxs(blk *types.Block) error {
+	m.blocks <- blk
+	return nil
+}
+
 func (m *mockMesh) GetRefBlock(id types.EpochID) types.BlockID {
 	return types.BlockID{}
 }
@@ -644,8 +782,6 @@ func (m *mockMesh) GetOrphanBlocksBefore(types.LayerID) ([]types.BlockID, error)
 }
 
 func TestBlockBuilder_getVotes(t *testing.T) {
-	rand.Seed(0)
-
 	r := require.New(t)
 	n1 := service.NewSimulator().NewNode()
 	allblocks := []*types.Block{b1, b2, b3, b4, b5, b6, b7}
@@ -722,6 +858,62 @@ func TestBlockBuilder_createBlock(t *testing.T) {
 	r.EqualError(err, "cannot create blockBytes in genesis layer")
 }
 
+func TestBlockBuilder_SkipEmptyBlocks(t *testing.T) {
+	r := require.New(t)
+	n1 := service.NewSimulator().NewNode()
+	types.SetLayersPerEpoch(int32(3))
+	txIDs := []types.TransactionID{types.TransactionID(types.CalcHash32([]byte("tx")))}
+
+	builder := createBlockBuilder("a", n1, nil)
+	builder.hareResult = &mockResult{}
+
+	// the first block in the epoch carries the active set, and thus isn't empty
+	_, err := builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, nil, nil)
+	r.NoError(err)
+	_, err = builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, nil, nil)
+	r.NoError(err)
+
+	builder = createBlockBuilder("b", n1, nil)
+	builder.hareResult = &mockResult{}
+	builder.skipEmpty = true
+
+	_, err = builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, nil, nil)
+	r.NoError(err)
+	_, err = builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, nil, nil)
+	r.Equal(ErrEmptyBlock, err)
+	b, err := builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, txIDs, nil)
+	r.NoError(err)
+	r.Equal(txIDs, b.TxIDs)
+}
+
+func TestBlockBuilder_MaxBlockBytes(t *testing.T) {
+	r := require.New(t)
+	n1 := service.NewSimulator().NewNode()
+	types.SetLayersPerEpoch(int32(3))
+	var txIDs []types.TransactionID
+	for i := 0; i < 50; i++ {
+		txIDs = append(txIDs, types.TransactionID(types.CalcHash32([]byte(fmt.Sprint(i)))))
+	}
+
+	builder := createBlockBuilder("a", n1, nil)
+	builder.hareResult = &mockResult{}
+	b, err := builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, txIDs, nil)
+	r.NoError(err)
+	r.Len(b.TxIDs, len(txIDs))
+	blockBytes, err := types.InterfaceToBytes(b)
+	r.NoError(err)
+
+	builder = createBlockBuilder("b", n1, nil)
+	builder.hareResult = &mockResult{}
+	builder.maxBlockBytes = len(blockBytes) - 10*len(types.TransactionID{}) - 1
+	b, err = builder.createBlock(7, types.ATXID{}, types.BlockEligibilityProof{}, txIDs, nil)
+	r.NoError(err)
+	r.Equal(txIDs[:39], b.TxIDs)
+	blockBytes, err = types.InterfaceToBytes(b)
+	r.NoError(err)
+	r.True(len(blockBytes) <= builder.maxBlockBytes)
+}
+
 func TestBlockBuilder_notSynced(t *testing.T) {
 	r := require.New(t)
 	beginRound := make(chan types.LayerID)
@@ -743,6 +935,153 @@ func TestBlockBuilder_notSynced(t *testing.T) {
 	r.Equal(0, mbo.calls)
 }
 
+func TestBlockBuilder_MultipleProofs(t *testing.T) {
+	r := require.New(t)
+	types.SetLayersPerEpoch(3)
+	beginRound := make(chan types.LayerID)
+	mesh := &recordingMesh{mockMesh: &mockMesh{}, blocks: make(chan *types.Block, 2)}
+
+	builder := createBlockBuilder("a", service.NewSimulator().NewNode(), nil)
+	builder.beginRoundEvent = beginRound
+	builder.blockOracle = &mockBlockOracle{numProofs: 2}
+	builder.meshProvider = mesh
+	builder.TransactionPool = state.NewTxMemPool()
+	r.NoError(builder.Start())
+	defer builder.Close()
+
+	beginRound <- 7
+	first, second := <-mesh.blocks, <-mesh.blocks
+	r.Equal(uint32(0), first.EligibilityProof.J)
+	r.NotNil(first.ActiveSet)
+	r.Nil(first.RefBlock)
+	r.Equal(uint32(1), second.EligibilityProof.J)
+	r.Nil(second.ActiveSet)
+	r.Equal(first.ID(), *second.RefBlock)
+}
+
+func TestBlockBuilder_OnLayerSkipped(t *testing.T) {
+	r := require.New(t)
+	beginRound := make(chan types.LayerID)
+	n1 := service.NewSimulator().NewNode()
+	ms := &mockSyncer{notSynced: true}
+
+	builder := createBlockBuilder("a", n1, nil)
+	builder.syncer = ms
+	builder.beginRoundEvent = beginRound
+	skipped := make(chan string, 2)
+	builder.OnLayerSkipped(func(layer types.LayerID, reason string) {
+		skipped <- fmt.Sprintf("%v: %v", layer, reason)
+	})
+	r.NoError(builder.Start())
+	defer builder.Close()
+
+	beginRound <- 1
+	beginRound <- 2
+	r.Equal("1: "+SkipReasonNotSynced, <-skipped)
+	r.Equal("2: "+SkipReasonNotSynced, <-skipped)
+}
+
+func TestBlockBuilder_OnLayerSkippedReasons(t *testing.T) {
+	r := require.New(t)
+	types.SetLayersPerEpoch(3)
+	beginRound := make(chan types.LayerID)
+	mesh := &recordingMesh{mockMesh: &mockMesh{}, blocks: make(chan *types.Block, 1)}
+	mbo := &mockBlockOracle{}
+
+	builder := createBlockBuilder("a", service.NewSimulator().NewNode(), nil)
+	builder.beginRoundEvent = beginRound
+	builder.blockOracle = mbo
+	builder.meshProvider = mesh
+	builder.TransactionPool = state.NewTxMemPool()
+	builder.skipEmpty = true
+	skipped := make(chan string, 1)
+	builder.OnLayerSkipped(func(layer types.LayerID, reason string) {
+		skipped <- fmt.Sprintf("%v: %v", layer, reason)
+	})
+	r.NoError(builder.Start())
+	defer builder.Close()
+
+	beginRound <- 1
+	r.Equal("1: "+SkipReasonGenesis, <-skipped)
+	r.Equal(0, mbo.calls)
+
+	// the epoch's first block is its ref block, so only the next layer's block is empty
+	beginRound <- 7
+	<-mesh.blocks
+	beginRound <- 8
+	r.Equal("8: "+SkipReasonEmptyBlock, <-skipped)
+
+	mbo.err = blocks.ErrNoATX
+	beginRound <- 9
+	r.Equal("9: "+SkipReasonNoATX, <-skipped)
+}
+
+// blockingResult blocks GetResult until released, to hold the builder in the middle of creating a block
+type blockingResult struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingResult) GetResult(types.LayerID) ([]types.BlockID, error) {
+	m.entered <- struct{}{}
+	<-m.release
+	return nil, nil
+}
+
+func TestBlockBuilder_RefBlockPersistedOnClose(t *testing.T) {
+	r := require.New(t)
+	types.SetLayersPerEpoch(3)
+	dir, err := ioutil.TempDir("", "builder")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+	database.SwitchCreationContext(dir, "")
+	defer database.SwitchToMemCreationContext()
+
+	beginRound := make(chan types.LayerID)
+	hare := &blockingResult{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	mesh := &recordingMesh{mockMesh: &mockMesh{}, blocks: make(chan *types.Block, 1)}
+	builder := createBlockBuilder("a", service.NewSimulator().NewNode(), nil)
+	builder.beginRoundEvent = beginRound
+	builder.hareResult = hare
+	builder.meshProvider = mesh
+	builder.TransactionPool = state.NewTxMemPool()
+	r.NoError(builder.Start())
+
+	// close the builder while it's creating the first block of the epoch, before it stored the ref block
+	beginRound <- 7
+	<-hare.entered
+	closed := make(chan error, 1)
+	go func() { closed <- builder.Close() }()
+	<-builder.stopChan
+	select {
+	case err := <-closed:
+		r.FailNow("builder closed while creating a block", "%v", err)
+	default:
+	}
+	close(hare.release)
+
+	var blk *types.Block
+	select {
+	case blk = <-mesh.blocks:
+	case <-time.After(time.Second):
+		r.FailNow("timed out waiting for the block")
+	}
+	select {
+	case err := <-closed:
+		r.NoError(err)
+	case <-time.After(time.Second):
+		r.FailNow("timed out waiting for the builder to close")
+	}
+
+	db, err := database.NewLDBDatabase(filepath.Join(dir, "builder"), 16, 16, log.NewDefault(t.Name()))
+	r.NoError(err)
+	defer db.Close()
+	builder.db = db
+	refBlock, err := builder.getRefBlock(types.LayerID(7).GetEpoch())
+	r.NoError(err)
+	r.Equal(blk.ID(), refBlock)
+}
+
 var (
 	block1ID = types.NewExistingBlock(1, []byte{1}, nil).ID()
 	block2ID = types.NewExistingBlock(1, []byte{2}, nil).ID()
@@ -202,6 +202,7 @@ func (tp *TransactionProcessor) ApplyRewards(layer types.LayerID, miners []types
 			Total:       rewardConverted,
 			LayerReward: rewardConverted * uint64(len(miners)),
 			Coinbase:    account,
+			Type:        events.RewardTypeBlock,
 		})
 	}
 	newHash, err := tp.Commit()
@@ -323,6 +324,10 @@ func (tp *TransactionProcessor) HandleTxData(data service.GossipMessage, syncer
 		tp.With().Error("failed to calc transaction origin", tx.ID(), log.Err(err))
 		return
 	}
+	if err := tx.Validate(); err != nil {
+		tp.With().Error("invalid transaction", tx.ID(), log.Err(err))
+		return
+	}
 	if !tp.AddressExists(tx.Origin()) {
 		tp.With().Error("transaction origin does not exist", log.String("transaction", tx.String()),
 			tx.ID(), log.String("origin", tx.Origin().Short()), log.Err(err))
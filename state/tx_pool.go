@@ -96,18 +96,24 @@ func getRandIdxs(numOfTxs, spaceSize int) map[uint64]struct{} {
 // Put inserts a transaction into the mem pool. It indexes it by source and dest addresses as well
 func (t *TxMempool) Put(id types.TransactionID, tx *types.Transaction) {
 	t.mu.Lock()
+	_, found := t.txs[id]
 	t.txs[id] = tx
 	t.getOrCreate(tx.Origin()).Add(0, tx)
 	t.addToAddr(tx.Origin(), id)
 	t.addToAddr(tx.Recipient, id)
+	pending := len(t.txs)
 	t.mu.Unlock()
 	events.ReportNewTx(tx)
+	if !found {
+		reportMempoolState(pending)
+	}
 }
 
 // Invalidate removes transaction from pool
 func (t *TxMempool) Invalidate(id types.TransactionID) {
 	t.mu.Lock()
-	if tx, found := t.txs[id]; found {
+	tx, found := t.txs[id]
+	if found {
 		if pendingTxs, found := t.accounts[tx.Origin()]; found {
 			// Once a tx appears in a block we want to invalidate all of this nonce's variants. The mempool currently
 			// only accepts one version, but this future-proofs it.
@@ -125,7 +131,25 @@ func (t *TxMempool) Invalidate(id types.TransactionID) {
 		t.removeFromAddr(tx.Origin(), id)
 		t.removeFromAddr(tx.Recipient, id)
 	}
+	pending := len(t.txs)
 	t.mu.Unlock()
+	if found {
+		reportMempoolState(pending)
+	}
+}
+
+// txSize is the size in bytes of a serialized transaction. Transactions only have fixed-size fields, so it's the same
+// for all of them.
+var txSize = func() uint64 {
+	txBytes, err := types.InterfaceToBytes(&types.Transaction{})
+	if err != nil {
+		panic(err)
+	}
+	return uint64(len(txBytes))
+}()
+
+func reportMempoolState(pending int) {
+	events.ReportMempoolState(pending, uint64(pending)*txSize)
 }
 
 // GetProjection returns the estimated nonce and balance for the provided address addr and previous nonce and balance
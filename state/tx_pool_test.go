@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/rand"
 	"github.com/spacemeshos/go-spacemesh/signing"
 	"github.com/stretchr/testify/require"
@@ -106,6 +107,39 @@ func TestTxPoolWithAccounts_GetRandomTxs(t *testing.T) {
 	*/
 }
 
+func TestTxPool_ReportMempoolState(t *testing.T) {
+	r := require.New(t)
+	r.NoError(events.InitializeEventReporterWithOptions("", 1, false))
+	defer events.CloseEventReporter()
+	stream := events.GetMempoolStateChannel()
+
+	pool := NewTxMemPool()
+	signer := signing.NewEdSigner()
+	tx1 := newTx(t, 4, 50, signer)
+	tx2 := newTx(t, 5, 150, signer)
+	txBytes, err := types.InterfaceToBytes(tx1)
+	r.NoError(err)
+	size := uint64(len(txBytes))
+
+	pool.Put(tx1.ID(), tx1)
+	r.Equal(events.MempoolState{Pending: 1, Bytes: size}, <-stream)
+
+	pool.Put(tx2.ID(), tx2)
+	r.Equal(events.MempoolState{Pending: 2, Bytes: 2 * size}, <-stream)
+
+	pool.Invalidate(tx1.ID())
+	r.Equal(events.MempoolState{Pending: 1, Bytes: size}, <-stream)
+
+	// putting a known tx or invalidating an unknown one doesn't change the mempool, so it isn't reported
+	pool.Put(tx2.ID(), tx2)
+	pool.Invalidate(tx1.ID())
+	select {
+	case state := <-stream:
+		r.FailNow("unexpected mempool state report", "%v", state)
+	default:
+	}
+}
+
 func TestGetRandIdxs(t *testing.T) {
 	seed := []byte("seedseed")
 	rand.Seed(int64(binary.LittleEndian.Uint64(seed)))
@@ -439,6 +439,7 @@ func (s *Syncer) handleNotSynced(currentSyncLayer types.LayerID) {
 
 	// first, bring all the data of the prev layers
 	// Note: lastTicked() is not constant but updates as ticks are received
+	startLayer, startTime := currentSyncLayer, time.Now()
 	for ; currentSyncLayer < s.GetCurrentLayer(); currentSyncLayer++ {
 		s.With().Info("syncing layer", log.FieldNamed("current_sync_layer", currentSyncLayer),
 			log.FieldNamed("last_ticked_layer", s.GetCurrentLayer()))
@@ -461,6 +462,9 @@ func (s *Syncer) handleNotSynced(currentSyncLayer types.LayerID) {
 		}
 		s.syncAtxs(currentSyncLayer)
 		s.ValidateLayer(lyr) // wait for layer validation
+
+		rate := float64(currentSyncLayer-startLayer+1) / time.Since(startTime).Seconds()
+		events.ReportSyncProgress(currentSyncLayer, s.GetCurrentLayer(), rate)
 	}
 
 	// if we are in the first epoch, we need to listen to gossip still
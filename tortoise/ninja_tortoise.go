@@ -108,6 +108,8 @@ type ninjaTortoise struct {
 	TGood map[types.LayerID]votingPattern // good pattern for layer i
 
 	TVote map[votingPattern]map[blockIDLayerTuple]vec // global opinion
+
+	TReported map[types.BlockID]vec // opinion last reported as included or orphaned for a block
 }
 
 // NewNinjaTortoise create a new ninja tortoise instance
@@ -131,6 +133,7 @@ func newNinjaTortoise(layerSize int, blocks database, hdist int, log log.Log) *n
 		TComplete:          map[votingPattern]struct{}{},
 		TEffectiveToBlocks: map[votingPattern][]blockIDLayerTuple{},
 		TPatSupport:        map[votingPattern]map[types.LayerID]votingPattern{},
+		TReported:          map[types.BlockID]vec{},
 	}
 
 	return trtl
@@ -147,6 +150,16 @@ func (ni *ninjaTortoise) saveOpinion() error {
 			ni.logger.With().Warning("block is contextually invalid", b.id())
 		}
 		events.ReportValidBlock(b.id(), valid)
+
+		// the opinion is saved on every persist, but a block is only reported once its fate is decided or changes
+		if (vec == support || vec == against) && ni.TReported[b.id()] != vec {
+			ni.TReported[b.id()] = vec
+			if vec == support {
+				events.ReportBlockIncluded(b.id(), b.layer())
+			} else {
+				events.ReportBlockOrphaned(b.id(), b.layer())
+			}
+		}
 	}
 	return nil
 }
@@ -199,6 +212,7 @@ func (ni *ninjaTortoise) evictOutOfPbase() {
 				delete(ni.TEffective, id)
 				delete(ni.TCorrect, id)
 				delete(ni.TExplicit, id)
+				delete(ni.TReported, id)
 				ni.logger.Debug("evict block %v from maps ", id)
 			}
 		}()
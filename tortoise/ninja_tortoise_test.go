@@ -3,6 +3,7 @@ package tortoise
 import (
 	"fmt"
 	"github.com/spacemeshos/go-spacemesh/common/types"
+	"github.com/spacemeshos/go-spacemesh/events"
 	"github.com/spacemeshos/go-spacemesh/log"
 	"github.com/spacemeshos/go-spacemesh/mesh"
 	"github.com/spacemeshos/go-spacemesh/rand"
@@ -1042,3 +1043,44 @@ func TestNinjaTortoise_Recovery(t *testing.T) {
 
 	alg.HandleIncomingLayer(l32) //crash
 }
+
+func TestNinjaTortoise_ReportBlocksOnce(t *testing.T) {
+	assert.NoError(t, events.InitializeEventReporterWithOptions("", 100, false))
+	defer events.CloseEventReporter()
+
+	mdb := getInMemMesh()
+	alg := newNinjaTortoise(3, mdb, 5, log.NewDefault(t.Name()))
+	l := mesh.GenesisLayer()
+	AddLayer(mdb, l)
+	alg.handleIncomingLayer(l)
+	prev := l
+	for i := types.LayerID(1); i < 5; i++ {
+		lyr := createLayer(i, []*types.Layer{prev, l}, 3)
+		AddLayer(mdb, lyr)
+		alg.handleIncomingLayer(lyr)
+		prev = lyr
+	}
+
+	reported := make(map[string]int)
+	drain := func() {
+		for {
+			select {
+			case b := <-events.GetBlockIncludedChannel():
+				reported[b.ID]++
+			case b := <-events.GetBlockOrphanedChannel():
+				reported[b.ID]++
+			default:
+				return
+			}
+		}
+	}
+
+	assert.NoError(t, alg.persist())
+	drain()
+	assert.NotEmpty(t, reported)
+	assert.NoError(t, alg.persist())
+	drain()
+	for id, n := range reported {
+		assert.Equal(t, 1, n, "block %v reported %v times", id, n)
+	}
+}